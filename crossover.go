@@ -0,0 +1,178 @@
+package main
+
+import "math/rand"
+
+// CrossoverFunc combines two parent tours into a new child tour. It is
+// passed to Evolve so callers can choose which permutation crossover to use.
+type CrossoverFunc func(tour1, tour2 *Tour) *Tour
+
+// cityIndex builds a lookup from city pointer to its position in cities.
+func cityIndex(cities []*City) map[*City]int {
+	index := make(map[*City]int, len(cities))
+	for i, c := range cities {
+		index[c] = i
+	}
+	return index
+}
+
+// OrderCrossover implements OX1: a random segment [a,b) is copied from
+// tour1 into the child at the same positions, and the remaining slots are
+// filled with the cities from tour2, in the order they appear starting
+// right after b (wrapping around), skipping any city already placed.
+func OrderCrossover(tour1, tour2 *Tour) *Tour {
+	n := len(tour1.cities)
+	child := make([]*City, n)
+	placed := make(map[*City]bool, n)
+
+	a := rand.Intn(n)
+	b := rand.Intn(n)
+	if a > b {
+		a, b = b, a
+	}
+	for i := a; i < b; i++ {
+		child[i] = tour1.cities[i]
+		placed[tour1.cities[i]] = true
+	}
+
+	pos := b % n
+	for j := 0; j < n; j++ {
+		city := tour2.cities[(b+j)%n]
+		if placed[city] {
+			continue
+		}
+		child[pos] = city
+		placed[city] = true
+		pos = (pos + 1) % n
+	}
+
+	newTour := &Tour{cities: child}
+	newTour.distance = newTour.CalculateDistance()
+	return newTour
+}
+
+// PartiallyMappedCrossover implements PMX: a random segment [a,b) is copied
+// from tour1 into the child at the same positions. For each city in
+// tour2's corresponding segment that isn't already placed, it conflicts
+// with the tour1 city occupying its slot, so its final index is found by
+// following the mapping induced by the two segments (tour1's city at an
+// index <-> tour2's index of that same city) until it reaches a slot
+// outside the segment. Remaining slots are then filled directly from
+// tour2.
+func PartiallyMappedCrossover(tour1, tour2 *Tour) *Tour {
+	n := len(tour1.cities)
+	child := make([]*City, n)
+	placed := make(map[*City]bool, n)
+
+	a := rand.Intn(n)
+	b := rand.Intn(n)
+	if a > b {
+		a, b = b, a
+	}
+	for i := a; i < b; i++ {
+		child[i] = tour1.cities[i]
+		placed[tour1.cities[i]] = true
+	}
+
+	index2 := cityIndex(tour2.cities)
+	for i := a; i < b; i++ {
+		city := tour2.cities[i]
+		if placed[city] {
+			continue
+		}
+		pos := i
+		for a <= pos && pos < b {
+			pos = index2[tour1.cities[pos]]
+		}
+		child[pos] = city
+		placed[city] = true
+	}
+
+	for i := 0; i < n; i++ {
+		if child[i] == nil {
+			child[i] = tour2.cities[i]
+		}
+	}
+
+	newTour := &Tour{cities: child}
+	newTour.distance = newTour.CalculateDistance()
+	return newTour
+}
+
+// EdgeRecombinationCrossover implements ERX: an adjacency list of each
+// city's neighbors across both parent tours is built, a random city is
+// chosen as the start, and the walk greedily continues to the unvisited
+// neighbor with the fewest remaining neighbors of its own, breaking ties
+// randomly and falling back to a random unvisited city if none remain.
+func EdgeRecombinationCrossover(tour1, tour2 *Tour) *Tour {
+	n := len(tour1.cities)
+	neighbors := make(map[*City]map[*City]bool, n)
+
+	addEdges := func(cities []*City) {
+		for i, city := range cities {
+			prev := cities[(i-1+n)%n]
+			next := cities[(i+1)%n]
+			if neighbors[city] == nil {
+				neighbors[city] = make(map[*City]bool)
+			}
+			neighbors[city][prev] = true
+			neighbors[city][next] = true
+		}
+	}
+	addEdges(tour1.cities)
+	addEdges(tour2.cities)
+
+	remaining := make([]*City, n)
+	copy(remaining, tour1.cities)
+
+	visited := make(map[*City]bool, n)
+	child := make([]*City, 0, n)
+
+	current := remaining[rand.Intn(n)]
+	child = append(child, current)
+	visited[current] = true
+
+	for len(child) < n {
+		for c := range neighbors {
+			delete(neighbors[c], current)
+		}
+
+		candidates := make([]*City, 0, 4)
+		for c := range neighbors[current] {
+			if !visited[c] {
+				candidates = append(candidates, c)
+			}
+		}
+
+		var next *City
+		if len(candidates) > 0 {
+			best := -1
+			var bestCities []*City
+			for _, c := range candidates {
+				size := len(neighbors[c])
+				if best == -1 || size < best {
+					best = size
+					bestCities = []*City{c}
+				} else if size == best {
+					bestCities = append(bestCities, c)
+				}
+			}
+			next = bestCities[rand.Intn(len(bestCities))]
+		} else {
+			unvisited := make([]*City, 0, n-len(child))
+			for _, c := range remaining {
+				if !visited[c] {
+					unvisited = append(unvisited, c)
+				}
+			}
+			next = unvisited[rand.Intn(len(unvisited))]
+		}
+
+		child = append(child, next)
+		visited[next] = true
+		current = next
+	}
+
+	newTour := &Tour{cities: child}
+	newTour.distance = newTour.CalculateDistance()
+	return newTour
+}
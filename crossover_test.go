@@ -0,0 +1,52 @@
+package main
+
+import "testing"
+
+// assertValidPermutation fails t if tour does not contain every city in
+// cities exactly once.
+func assertValidPermutation(t *testing.T, cities []*City, tour *Tour) {
+	t.Helper()
+	if len(tour.cities) != len(cities) {
+		t.Fatalf("got %d cities, want %d", len(tour.cities), len(cities))
+	}
+	seen := make(map[*City]bool, len(cities))
+	for _, c := range tour.cities {
+		if seen[c] {
+			t.Fatalf("city %v appears more than once in child tour", c)
+		}
+		seen[c] = true
+	}
+	for _, c := range cities {
+		if !seen[c] {
+			t.Fatalf("city %v missing from child tour", c)
+		}
+	}
+}
+
+// TestCrossoverFuncsProduceValidPermutations guards against regressions
+// like PartiallyMappedCrossover's mapping-direction bug, which silently
+// produced children with duplicate and missing cities for most segment
+// choices.
+func TestCrossoverFuncsProduceValidPermutations(t *testing.T) {
+	cities := make([]*City, 8)
+	for i := range cities {
+		cities[i] = &City{x: i, y: i * 2}
+	}
+
+	crossovers := map[string]CrossoverFunc{
+		"OrderCrossover":             OrderCrossover,
+		"PartiallyMappedCrossover":   PartiallyMappedCrossover,
+		"EdgeRecombinationCrossover": EdgeRecombinationCrossover,
+	}
+
+	for name, crossover := range crossovers {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 200; i++ {
+				tour1 := NewTour(cities)
+				tour2 := NewTour(cities)
+				child := crossover(tour1, tour2)
+				assertValidPermutation(t, cities, child)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import "math"
+
+// GAConfig bundles the tunables that previously had to be threaded
+// through Evolve, RunIslandModel and runIsland as individual parameters.
+// It also carries the convergence controls added in this change:
+// stagnation-based early stopping and adaptive mutation.
+type GAConfig struct {
+	PopulationSize int
+	MaxGenerations int
+	CrossoverRate  float64
+	Crossover      CrossoverFunc
+	Selection      SelectionFunc
+
+	// MutationRate is the starting (and minimum) mutation rate.
+	// MaxMutationRate is the ceiling adaptive mutation can raise it to.
+	MutationRate    float64
+	MaxMutationRate float64
+
+	// Patience is the number of generations without an improvement of at
+	// least Epsilon in the best distance before a run stops early.
+	// Zero disables early stopping.
+	Patience int
+	Epsilon  float64
+
+	// DiversityFloor is the population-diversity value (stdev of tour
+	// distances) below which mutation rate ramps up toward
+	// MaxMutationRate; above it, mutation rate decays back toward
+	// MutationRate. See adaptMutationRate.
+	DiversityFloor float64
+
+	Migration MigrationConfig
+
+	// HybridizeEvery is the number of generations between local-search
+	// passes; HybridizeTopK and TwoOptMaxPasses configure that pass. Zero
+	// HybridizeEvery disables local search.
+	HybridizeEvery  int
+	HybridizeTopK   int
+	TwoOptMaxPasses int
+	DistanceMatrix  [][]float64
+}
+
+// GenerationStat records one generation's convergence metrics so callers
+// can plot best/mean distance and diversity over time.
+type GenerationStat struct {
+	Generation int
+	Best       float64
+	Mean       float64
+	Diversity  float64
+}
+
+// populationStats computes the best distance, mean distance, and
+// diversity (standard deviation of distance) across population.
+func populationStats(population []*Tour) (best, mean, diversity float64) {
+	best = population[0].distance
+	sum := 0.0
+	for _, tour := range population {
+		sum += tour.distance
+		if tour.distance < best {
+			best = tour.distance
+		}
+	}
+	mean = sum / float64(len(population))
+
+	variance := 0.0
+	for _, tour := range population {
+		d := tour.distance - mean
+		variance += d * d
+	}
+	variance /= float64(len(population))
+	diversity = math.Sqrt(variance)
+	return best, mean, diversity
+}
+
+// adaptMutationRate raises rate toward cfg.MaxMutationRate when the
+// population has converged (diversity below cfg.DiversityFloor), and
+// decays it back toward cfg.MutationRate once diversity recovers.
+func adaptMutationRate(rate, diversity float64, cfg *GAConfig) float64 {
+	if cfg.DiversityFloor <= 0 {
+		return rate
+	}
+	if diversity < cfg.DiversityFloor {
+		rate += (cfg.MaxMutationRate - cfg.MutationRate) * 0.05
+		if rate > cfg.MaxMutationRate {
+			rate = cfg.MaxMutationRate
+		}
+	} else {
+		rate -= (cfg.MaxMutationRate - cfg.MutationRate) * 0.05
+		if rate < cfg.MutationRate {
+			rate = cfg.MutationRate
+		}
+	}
+	return rate
+}
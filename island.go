@@ -0,0 +1,318 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// MigrationPolicy selects how islands exchange migrants.
+type MigrationPolicy int
+
+const (
+	// MigrationRing sends each island's migrants to its single successor
+	// in a fixed ring (island i -> island (i+1)%n).
+	MigrationRing MigrationPolicy = iota
+	// MigrationRandom sends each island's migrants to one other randomly
+	// chosen island each round.
+	MigrationRandom
+	// MigrationBroadcast sends each island's migrants to every other
+	// island.
+	MigrationBroadcast
+)
+
+// MigrationConfig controls how often and how many individuals migrate
+// between islands, and by which policy.
+type MigrationConfig struct {
+	Policy   MigrationPolicy
+	Size     int // number of individuals exchanged per migration round
+	Interval int // generations between migration rounds; 0 disables migration
+}
+
+// islandBarrier is a cyclic barrier: Wait blocks until n goroutines have
+// all called it, then releases them all together.
+type islandBarrier struct {
+	n     int
+	mu    sync.Mutex
+	count int
+	ch    chan struct{}
+}
+
+func newIslandBarrier(n int) *islandBarrier {
+	return &islandBarrier{n: n, ch: make(chan struct{})}
+}
+
+func (b *islandBarrier) Wait() {
+	b.mu.Lock()
+	b.count++
+	if b.count == b.n {
+		b.count = 0
+		ch := b.ch
+		b.ch = make(chan struct{})
+		b.mu.Unlock()
+		close(ch)
+		return
+	}
+	ch := b.ch
+	b.mu.Unlock()
+	<-ch
+}
+
+// sendMigrants delivers migrants from island id to one or more inboxes
+// according to policy.
+func sendMigrants(id int, migrants []*Tour, policy MigrationPolicy, inboxes []chan []*Tour) {
+	numIslands := len(inboxes)
+	switch policy {
+	case MigrationRing:
+		inboxes[(id+1)%numIslands] <- migrants
+	case MigrationRandom:
+		target := rand.Intn(numIslands - 1)
+		if target >= id {
+			target++
+		}
+		inboxes[target] <- migrants
+	case MigrationBroadcast:
+		for j := 0; j < numIslands; j++ {
+			if j != id {
+				inboxes[j] <- migrants
+			}
+		}
+	}
+}
+
+// drainInbox non-blockingly collects every migrant batch currently queued
+// in inbox.
+func drainInbox(inbox chan []*Tour) []*Tour {
+	var incoming []*Tour
+	for {
+		select {
+		case batch := <-inbox:
+			incoming = append(incoming, batch...)
+		default:
+			return incoming
+		}
+	}
+}
+
+// absorbMigrants replaces the worst individuals of a (distance-sorted)
+// population with incoming migrants, keeping population sorted.
+func absorbMigrants(population []*Tour, incoming []*Tour) []*Tour {
+	if len(incoming) == 0 {
+		return population
+	}
+	n := len(population)
+	replace := len(incoming)
+	if replace > n {
+		replace = n
+	}
+	copy(population[n-replace:], incoming[:replace])
+	sort.Slice(population, func(i, j int) bool {
+		return population[i].distance < population[j].distance
+	})
+	return population
+}
+
+// IslandResult is one island's outcome: its fittest tour and its
+// per-generation convergence history.
+type IslandResult struct {
+	Island int
+	Best   *Tour
+	Stats  []GenerationStat
+}
+
+// stagnationTracker aggregates per-island stagnation state so that
+// islands only stop once every island agrees the run has converged. An
+// island that hit its own patience limit still has to keep showing up to
+// the migration barrier (it just records itself as stagnant); this is
+// what prevents the deadlock that per-island early exit caused, since the
+// barrier requires every island to call Wait on every round for as long
+// as any island is still running.
+//
+// set and allStagnant are only safe to call at the barrier rendezvous
+// points runIsland uses around them: every island must call set before
+// any island calls allStagnant, and every island must call allStagnant
+// before any island calls set again for the next round. Without that
+// second guarantee a fast island could race ahead and overwrite its
+// entry for the next checkpoint while a slower sibling is still reading
+// the current one, so islands could observe different aggregates and
+// disagree on whether to stop — reintroducing the same deadlock this
+// type exists to prevent.
+type stagnationTracker struct {
+	mu       sync.Mutex
+	stagnant []bool
+}
+
+func newStagnationTracker(n int) *stagnationTracker {
+	return &stagnationTracker{stagnant: make([]bool, n)}
+}
+
+// set records island id's current stagnation state.
+func (t *stagnationTracker) set(id int, isStagnant bool) {
+	t.mu.Lock()
+	t.stagnant[id] = isStagnant
+	t.mu.Unlock()
+}
+
+// allStagnant reports whether every island has been recorded as stagnant.
+func (t *stagnationTracker) allStagnant() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, stagnant := range t.stagnant {
+		if !stagnant {
+			return false
+		}
+	}
+	return true
+}
+
+// RunIslandModel solves a single TSP instance using numIslands independent
+// subpopulations run in parallel, each in its own goroutine. Every
+// cfg.Migration.Interval generations, islands exchange their top
+// cfg.Migration.Size individuals following cfg.Migration.Policy,
+// synchronizing on a barrier so every island migrates at the same
+// generation. It returns the best tour found across all islands plus
+// every island's convergence history.
+func RunIslandModel(cities []*City, numIslands int, cfg *GAConfig) (*Tour, []IslandResult) {
+	inboxes := make([]chan []*Tour, numIslands)
+	for i := range inboxes {
+		inboxes[i] = make(chan []*Tour, numIslands)
+	}
+	barrier := newIslandBarrier(numIslands)
+	tracker := newStagnationTracker(numIslands)
+
+	results := make(chan IslandResult, numIslands)
+	var wg sync.WaitGroup
+	wg.Add(numIslands)
+
+	for id := 0; id < numIslands; id++ {
+		go func(id int) {
+			defer wg.Done()
+			best, stats := runIsland(id, cities, cfg, inboxes, barrier, tracker)
+			results <- IslandResult{Island: id, Best: best, Stats: stats}
+		}(id)
+	}
+
+	wg.Wait()
+	close(results)
+
+	var best *Tour
+	all := make([]IslandResult, 0, numIslands)
+	for r := range results {
+		all = append(all, r)
+		if best == nil || r.Best.distance < best.distance {
+			best = r.Best
+		}
+	}
+	return best, all
+}
+
+// runIsland evolves a single island's subpopulation, migrating with its
+// peers through inboxes every cfg.Migration.Interval generations, and
+// otherwise runs for cfg.MaxGenerations. Mutation rate adapts between
+// cfg.MutationRate and cfg.MaxMutationRate based on population diversity.
+// It returns the island's fittest tour and its per-generation convergence
+// stats.
+//
+// Once this island goes cfg.Patience generations without the best
+// distance improving by at least cfg.Epsilon, it records itself stagnant
+// in tracker but keeps evolving and keeps showing up to the migration
+// barrier — it only actually stops once every island has agreed the run
+// is stagnant, and all islands act on that agreement at the same
+// rendezvous, since dropping out unilaterally (or disagreeing on the
+// verdict) would leave some survivors blocked forever on a barrier that
+// needs all of them.
+func runIsland(id int, cities []*City, cfg *GAConfig, inboxes []chan []*Tour, barrier *islandBarrier, tracker *stagnationTracker) (*Tour, []GenerationStat) {
+	population := make([]*Tour, cfg.PopulationSize)
+	for i := range population {
+		population[i] = NewTour(cities)
+	}
+	for i := range population {
+		population[i].CalculateFitness()
+	}
+
+	migrating := cfg.Migration.Interval > 0 && len(inboxes) > 1
+	mutationRate := cfg.MutationRate
+	bestSeen := math.Inf(1)
+	stagnantFor := 0
+
+	stats := make([]GenerationStat, 0, cfg.MaxGenerations)
+	for gen := 0; gen < cfg.MaxGenerations; gen++ {
+		for _, tour := range population {
+			tour.fitness = 1.0 / tour.distance
+		}
+		population = Evolve(population, cfg.CrossoverRate, mutationRate, cities, cfg.Crossover, cfg.Selection)
+
+		if cfg.HybridizeEvery > 0 && gen%cfg.HybridizeEvery == 0 {
+			HybridizePopulation(population, cfg.DistanceMatrix, cfg.HybridizeTopK, cfg.TwoOptMaxPasses)
+		}
+
+		best, mean, diversity := populationStats(population)
+		stats = append(stats, GenerationStat{Generation: gen, Best: best, Mean: mean, Diversity: diversity})
+		mutationRate = adaptMutationRate(mutationRate, diversity, cfg)
+
+		if bestSeen-best > cfg.Epsilon {
+			bestSeen = best
+			stagnantFor = 0
+		} else {
+			stagnantFor++
+		}
+		isStagnant := cfg.Patience > 0 && stagnantFor >= cfg.Patience
+
+		if !migrating {
+			if isStagnant {
+				break
+			}
+			continue
+		}
+
+		migrationCheckpoint := gen%cfg.Migration.Interval == cfg.Migration.Interval-1
+		if !migrationCheckpoint {
+			continue
+		}
+
+		// Record this island's stagnation before the first barrier, so
+		// every island has written before any island reads the aggregate.
+		tracker.set(id, isStagnant)
+
+		// Cloned, not aliased: population[:Size] keeps evolving on this
+		// island after it migrates, so sharing the *Tour pointers
+		// themselves would let two islands mutate the same Tour
+		// concurrently (e.g. both writing fitness in their own
+		// generation loop).
+		migrants := make([]*Tour, cfg.Migration.Size)
+		for i, tour := range population[:cfg.Migration.Size] {
+			migrants[i] = tour.Clone()
+		}
+		sendMigrants(id, migrants, cfg.Migration.Policy, inboxes)
+
+		barrier.Wait()
+		population = absorbMigrants(population, drainInbox(inboxes[id]))
+		barrier.Wait()
+
+		// A third rendezvous before anyone acts on the aggregate: without
+		// it, an island that reads allStagnant false here is free to race
+		// ahead to its next checkpoint and call set again, mutating the
+		// very snapshot a slower sibling is about to read — so siblings
+		// could observe different aggregates for what should be one
+		// shared decision and disagree on whether to stop. Holding every
+		// island here until all of them have read ensures they decide
+		// together: either all break now, or all continue to the next
+		// checkpoint, so the barrier above this one never loses a
+		// participant.
+		shouldStop := tracker.allStagnant()
+		barrier.Wait()
+
+		if shouldStop {
+			break
+		}
+	}
+
+	best := population[0]
+	for _, tour := range population {
+		if tour.distance < best.distance {
+			best = tour
+		}
+	}
+	return best, stats
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// TestRunIslandModelStopsTogetherOnStagnation guards against the
+// migration-barrier deadlock that per-island early stopping used to
+// cause: once any island exits the barrier rotation, every survivor that
+// later reaches a migration checkpoint blocks forever waiting for a
+// participant that will never show up again.
+func TestRunIslandModelStopsTogetherOnStagnation(t *testing.T) {
+	cities := make([]*City, 12)
+	for i := range cities {
+		cities[i] = &City{x: rand.Intn(100), y: rand.Intn(100)}
+	}
+
+	cfg := &GAConfig{
+		PopulationSize:  20,
+		MaxGenerations:  5000,
+		CrossoverRate:   0.7,
+		Crossover:       OrderCrossover,
+		Selection:       TournamentSelect(3),
+		MutationRate:    0.05,
+		MaxMutationRate: 0.3,
+		Patience:        40,
+		Epsilon:         1e-9,
+		DiversityFloor:  1.0,
+		Migration: MigrationConfig{
+			Policy:   MigrationRing,
+			Size:     2,
+			Interval: 15,
+		},
+		DistanceMatrix: NewDistanceMatrix(cities),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		RunIslandModel(cities, 6, cfg)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(20 * time.Second):
+		t.Fatal("RunIslandModel did not return: islands likely deadlocked on the migration barrier")
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import "sort"
+
+// NewDistanceMatrix precomputes the pairwise distances between cities so
+// that TwoOpt and OrOpt can evaluate candidate moves in O(1). It assigns
+// each city's id as its index in cities, which TwoOpt/OrOpt rely on to
+// index into the returned matrix; call it once per problem, before
+// building the initial population.
+func NewDistanceMatrix(cities []*City) [][]float64 {
+	n := len(cities)
+	dm := make([][]float64, n)
+	for i, c := range cities {
+		c.id = i
+		dm[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			d := cities[i].Distance(cities[j])
+			dm[i][j] = d
+			dm[j][i] = d
+		}
+	}
+	return dm
+}
+
+// reverseSegment reverses cities[i:j+1] in place.
+func reverseSegment(cities []*City, i, j int) {
+	for i < j {
+		cities[i], cities[j] = cities[j], cities[i]
+		i++
+		j--
+	}
+}
+
+// TwoOpt repeatedly scans all pairs of edges (i,i+1) and (j,j+1) and
+// reverses the segment between them whenever that shortens the tour,
+// stopping once a full pass finds no improving swap or maxPasses is
+// reached. dm must come from NewDistanceMatrix over the same cities.
+func (t *Tour) TwoOpt(dm [][]float64, maxPasses int) {
+	n := len(t.cities)
+	for pass, improved := 0, true; improved && pass < maxPasses; pass++ {
+		improved = false
+		for i := 0; i < n-1; i++ {
+			a, b := t.cities[i], t.cities[i+1]
+			for j := i + 2; j < n; j++ {
+				if i == 0 && j == n-1 {
+					continue // reverses the whole tour: a no-op
+				}
+				c, d := t.cities[j], t.cities[(j+1)%n]
+				delta := (dm[a.id][c.id] + dm[b.id][d.id]) - (dm[a.id][b.id] + dm[c.id][d.id])
+				if delta < -1e-9 {
+					reverseSegment(t.cities, i+1, j)
+					b = t.cities[i+1]
+					improved = true
+				}
+			}
+		}
+	}
+	t.distance = t.CalculateDistance()
+}
+
+// relocateChain moves the segLen cities starting at i to just after the
+// city that was at index j (before removal), rebuilding the tour in place.
+func relocateChain(cities []*City, i, segLen, j int) {
+	segment := make([]*City, segLen)
+	copy(segment, cities[i:i+segLen])
+
+	rest := make([]*City, 0, len(cities)-segLen)
+	rest = append(rest, cities[:i]...)
+	rest = append(rest, cities[i+segLen:]...)
+
+	target := cities[j]
+	insertAt := len(rest)
+	for idx, c := range rest {
+		if c == target {
+			insertAt = idx + 1
+			break
+		}
+	}
+
+	result := make([]*City, 0, len(cities))
+	result = append(result, rest[:insertAt]...)
+	result = append(result, segment...)
+	result = append(result, rest[insertAt:]...)
+	copy(cities, result)
+}
+
+// relocateBestChain looks for a profitable relocation of the chain of
+// length segLen starting at index i and applies the first one found,
+// reporting whether it moved anything.
+func relocateBestChain(t *Tour, dm [][]float64, i, segLen int) bool {
+	n := len(t.cities)
+	if i+segLen > n {
+		return false
+	}
+	prev := t.cities[(i-1+n)%n]
+	segStart := t.cities[i]
+	segEnd := t.cities[i+segLen-1]
+	next := t.cities[(i+segLen)%n]
+	if prev == segEnd || next == segStart {
+		return false
+	}
+	removeGain := dm[prev.id][segStart.id] + dm[segEnd.id][next.id] - dm[prev.id][next.id]
+
+	for j := 0; j < n; j++ {
+		if j >= i-1 && j < i+segLen {
+			continue
+		}
+		a := t.cities[j]
+		b := t.cities[(j+1)%n]
+		if a == segEnd || b == segStart {
+			continue
+		}
+		insertCost := dm[a.id][segStart.id] + dm[segEnd.id][b.id] - dm[a.id][b.id]
+		if insertCost-removeGain < -1e-9 {
+			relocateChain(t.cities, i, segLen, j)
+			return true
+		}
+	}
+	return false
+}
+
+// OrOpt relocates short chains of 1, 2, or 3 consecutive cities to a
+// better position in the tour, repeating until a full pass over all chain
+// lengths finds no improving move or maxPasses is reached.
+func (t *Tour) OrOpt(dm [][]float64, maxPasses int) {
+	n := len(t.cities)
+	for pass, improved := 0, true; improved && pass < maxPasses; pass++ {
+		improved = false
+		for segLen := 1; segLen <= 3 && segLen < n-1; segLen++ {
+			for i := 0; i < n; i++ {
+				if relocateBestChain(t, dm, i, segLen) {
+					improved = true
+				}
+			}
+		}
+	}
+	t.distance = t.CalculateDistance()
+}
+
+// HybridizePopulation applies TwoOpt followed by OrOpt to the topK best
+// (i.e. first, since population is kept sorted by distance) tours in
+// population, then re-sorts so the improved tours take their new rank.
+// This is the memetic-algorithm step: it's meant to be called every few
+// generations from the GA loop, not on every individual every generation,
+// since local search is far more expensive than a crossover or mutation.
+func HybridizePopulation(population []*Tour, dm [][]float64, topK, maxPasses int) {
+	if topK > len(population) {
+		topK = len(population)
+	}
+	for i := 0; i < topK; i++ {
+		population[i].TwoOpt(dm, maxPasses)
+		population[i].OrOpt(dm, maxPasses)
+	}
+	sort.Slice(population, func(i, j int) bool {
+		return population[i].distance < population[j].distance
+	})
+}
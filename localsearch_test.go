@@ -0,0 +1,63 @@
+package main
+
+import "testing"
+
+// TestLocalSearchPreservesPermutationAndNeverWorsens fuzzes TwoOpt and
+// OrOpt over random instances, checking both that they never produce an
+// invalid tour and that they never report a distance worse than the one
+// they started from — the two ways local search has silently corrupted
+// a tour elsewhere in this series (PMX, migrant aliasing).
+func TestLocalSearchPreservesPermutationAndNeverWorsens(t *testing.T) {
+	for trial := 0; trial < 50; trial++ {
+		cities := make([]*City, 20)
+		for i := range cities {
+			cities[i] = &City{x: (i*37 + trial*13) % 200, y: (i*53 + trial*7) % 200}
+		}
+		dm := NewDistanceMatrix(cities)
+
+		tour := NewTour(cities)
+		before := tour.distance
+
+		tour.TwoOpt(dm, 20)
+		tour.OrOpt(dm, 20)
+
+		assertValidPermutation(t, cities, tour)
+		if tour.distance > before+1e-9 {
+			t.Fatalf("trial %d: distance worsened from %v to %v", trial, before, tour.distance)
+		}
+	}
+}
+
+// TestHybridizePopulationPreservesPermutationAndNeverWorsens checks the
+// same two properties through HybridizePopulation, the entry point the
+// GA loop actually calls.
+func TestHybridizePopulationPreservesPermutationAndNeverWorsens(t *testing.T) {
+	cities := make([]*City, 16)
+	for i := range cities {
+		cities[i] = &City{x: i * 7 % 97, y: i * 11 % 89}
+	}
+	dm := NewDistanceMatrix(cities)
+
+	population := make([]*Tour, 10)
+	before := make([]float64, len(population))
+	for i := range population {
+		population[i] = NewTour(cities)
+		before[i] = population[i].distance
+	}
+
+	HybridizePopulation(population, dm, len(population), 20)
+
+	bestBefore := before[0]
+	for _, d := range before {
+		if d < bestBefore {
+			bestBefore = d
+		}
+	}
+
+	for _, tour := range population {
+		assertValidPermutation(t, cities, tour)
+	}
+	if population[0].distance > bestBefore+1e-9 {
+		t.Fatalf("best distance after hybridization (%v) worse than best before (%v)", population[0].distance, bestBefore)
+	}
+}
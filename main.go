@@ -2,9 +2,9 @@ package main
 
 import (
 	"encoding/csv"
+	"flag"
 	"fmt"
 	"image"
-	"image/color"
 	"image/png"
 	"log"
 	"math"
@@ -20,15 +20,36 @@ const NodeSize = 10
 
 // City represents a city with x and y coordinates
 type City struct {
-	x int
-	y int
+	x  int
+	y  int
+	id int // index into the problem's distance matrix, set by NewDistanceMatrix
+
+	// weightType selects which TSPLIB distance formula Distance uses; the
+	// zero value behaves as EUC_2D, plain Euclidean distance over x/y.
+	// Set by ParseTSPFile and carried over by RescaleCities.
+	weightType edgeWeightType
+	// lat and lon are the original GEO coordinates in radians, set by
+	// ParseTSPFile for GEO instances. x/y still carry a scaled-up,
+	// fixed-point version of the same values so GEO cities can be drawn
+	// and rescaled like any other instance, but Distance uses lat/lon
+	// directly for the real great-circle formula, since RescaleCities'
+	// linear rescale of x/y would otherwise corrupt it.
+	lat, lon float64
 }
 
-// Distance returns the distance to another city
+// Distance returns the distance to another city, using the TSPLIB
+// distance formula for city's weightType.
 func (city *City) Distance(other *City) float64 {
-	xDistance := math.Abs(float64(city.x - other.x))
-	yDistance := math.Abs(float64(city.y - other.y))
-	return math.Sqrt(xDistance*xDistance + yDistance*yDistance)
+	switch city.weightType {
+	case edgeWeightGEO:
+		return geoDistance(city, other)
+	case edgeWeightATT:
+		return attDistance(city, other)
+	default:
+		xDistance := math.Abs(float64(city.x - other.x))
+		yDistance := math.Abs(float64(city.y - other.y))
+		return math.Sqrt(xDistance*xDistance + yDistance*yDistance)
+	}
 }
 
 // Tour represents a tour of cities
@@ -51,6 +72,15 @@ func NewTour(cities []*City) *Tour {
 	return tour
 }
 
+// Clone returns a deep copy of tour: a copy of the cities slice plus
+// its own distance and fitness, so the copy can cross into another
+// goroutine (e.g. as a migrant) without aliasing the original.
+func (tour *Tour) Clone() *Tour {
+	cities := make([]*City, len(tour.cities))
+	copy(cities, tour.cities)
+	return &Tour{cities: cities, distance: tour.distance, fitness: tour.fitness}
+}
+
 // CalculateDistance calculates the total distance of the tour
 func (tour *Tour) CalculateDistance() float64 {
 	distance := 0.0
@@ -79,39 +109,18 @@ func contains(cities []*City, city *City) bool {
 	return false
 }
 
-// Crossover creates a new tour by crossing over two tours at a random point
-func Crossover(tour1, tour2 *Tour) *Tour {
-	newTour := &Tour{
-		cities: make([]*City, len(tour1.cities)),
-	}
-	for i := 0; i < len(newTour.cities)/2; i++ {
-		newTour.cities[i] = tour1.cities[i]
-	}
-	i := len(newTour.cities) / 2
-	j := 0
-	for j < len(tour2.cities) {
-		if !contains(newTour.cities, tour2.cities[j]) {
-			newTour.cities[i] = tour2.cities[j]
-			i++
-		}
-		j++
-	}
-	newTour.distance = newTour.CalculateDistance()
-	return newTour
-}
-
 // Evolve runs the genetic algorithm on the given population of tours
-func Evolve(population []*Tour, crossoverRate float64, mutationRate float64, cities []*City) []*Tour {
+func Evolve(population []*Tour, crossoverRate float64, mutationRate float64, cities []*City, crossover CrossoverFunc, selection SelectionFunc) []*Tour {
 	newPopulation := make([]*Tour, len(population))
 
 	for i := 0; i < len(newPopulation); i++ {
 		if i < len(population)/2 {
 			newPopulation[i] = population[i]
 		} else {
-			tour1 := SelectTour(population)
-			tour2 := SelectTour(population)
+			tour1 := selection(population)
+			tour2 := selection(population)
 			if rand.Float64() < crossoverRate {
-				newPopulation[i] = Crossover(tour1, tour2)
+				newPopulation[i] = crossover(tour1, tour2)
 			} else {
 				newPopulation[i] = NewTour(cities)
 			}
@@ -127,105 +136,54 @@ func Evolve(population []*Tour, crossoverRate float64, mutationRate float64, cit
 	return newPopulation
 }
 
-// SelectTour selects a tour from the given population using roulette wheel selection
-func SelectTour(population []*Tour) *Tour {
-	fitnessSum := 0.0
-	for _, tour := range population {
-		fitnessSum += tour.fitness
-	}
-	randNum := rand.Float64() * fitnessSum
-	curSum := 0.0
-	for _, tour := range population {
-		curSum += tour.fitness
-		if curSum >= randNum {
-			return tour
-		}
-	}
-	return population[0]
-}
-
-// DrawTour draws the given tour on the image
-func DrawTour(tour *Tour, img *image.RGBA) {
-	for _, city := range tour.cities {
-		for x := city.x - NodeSize; x <= city.x+NodeSize; x++ {
-			for y := city.y - NodeSize; y <= city.y+NodeSize; y++ {
-				img.Set(x, y, color.RGBA{0, 0, 0, 255})
-			}
-		}
-	}
-
-	for i := 0; i < len(tour.cities)-1; i++ {
-		DrawLine(tour.cities[i], tour.cities[i+1], img)
-	}
-	DrawLine(tour.cities[len(tour.cities)-1], tour.cities[0], img)
+// CalculateFitness calculates the fitness of a tour based on its distance
+func (tour *Tour) CalculateFitness() {
+	tour.fitness = 1.0 / tour.distance
 }
 
-// DrawLine draws a line between the two cities on the image
-func DrawLine(city1, city2 *City, img *image.RGBA) {
-	dx := city2.x - city1.x
-	dy := city2.y - city1.y
-	if dx == 0 {
-		if dy > 0 {
-			for y := city1.y; y <= city2.y; y++ {
-				img.Set(city1.x, y, color.RGBA{0, 0, 0, 255})
-			}
-		} else {
-			for y := city2.y; y <= city1.y; y++ {
-				img.Set(city1.x, y, color.RGBA{0, 0, 0, 255})
-			}
-		}
-		return
-	}
-	slope := float64(dy) / float64(dx)
-	if math.Abs(slope) > 1 {
-		if dy > 0 {
-			for y := city1.y; y <= city2.y; y++ {
-				x := int(float64(y-city1.y)/slope + float64(city1.x))
-				img.Set(x, y, color.RGBA{0, 0, 0, 255})
-			}
-		} else {
-			for y := city2.y; y <= city1.y; y++ {
-				x := int(float64(y-city1.y)/slope + float64(city1.x))
-				img.Set(x, y, color.RGBA{0, 0, 0, 255})
-			}
-		}
-	} else {
-		if dx > 0 {
-			for x := city1.x; x <= city2.x; x++ {
-				y := int(slope*float64(x-city1.x) + float64(city1.y))
-				img.Set(x, y, color.RGBA{0, 0, 0, 255})
-			}
-		} else {
-			for x := city2.x; x <= city1.x; x++ {
-				y := int(slope*float64(x-city1.x) + float64(city1.y))
-				img.Set(x, y, color.RGBA{0, 0, 0, 255})
-			}
+// finalGeneration returns the generation count reached by the island that
+// produced bestTour, for progress overlays; 0 if it can't be found.
+func finalGeneration(bestTour *Tour, islands []IslandResult) int {
+	for _, island := range islands {
+		if island.Best == bestTour && len(island.Stats) > 0 {
+			return island.Stats[len(island.Stats)-1].Generation
 		}
 	}
-}
-
-// CalculateFitness calculates the fitness of a tour based on its distance
-func (tour *Tour) CalculateFitness() {
-	tour.fitness = 1.0 / tour.distance
+	return 0
 }
 
 func main() {
-	const numProblems = 6
 	const numCities = 32
 	const maxX, maxY = 256, 256
 	const numThreads = 12
 
-	// Generate random cities
-	var problems [numProblems][]*City
-	for i := 0; i < numProblems; i++ {
-		problems[i] = make([]*City, numCities)
-		for j := 0; j < numCities; j++ {
-			problems[i][j] = &City{
-				x: rand.Intn(maxX),
-				y: rand.Intn(maxY),
+	tspPath := flag.String("tsp", "", "path to a TSPLIB .tsp file or a directory of .tsp files; when unset, random instances are generated")
+	flag.Parse()
+
+	// Generate or load problems
+	var problems [][]*City
+	if *tspPath != "" {
+		benchmarks, err := LoadTSPBenchmarks(*tspPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, cities := range benchmarks {
+			problems = append(problems, RescaleCities(cities, maxX, maxY))
+		}
+	} else {
+		const numProblems = 6
+		problems = make([][]*City, numProblems)
+		for i := 0; i < numProblems; i++ {
+			problems[i] = make([]*City, numCities)
+			for j := 0; j < numCities; j++ {
+				problems[i][j] = &City{
+					x: rand.Intn(maxX),
+					y: rand.Intn(maxY),
+				}
 			}
 		}
 	}
+	numProblems := len(problems)
 
 	// Open file to write results to
 	file, err := os.Create("results.txt")
@@ -251,7 +209,20 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// Create channels to hold data for each problem
+	// Create a CSV file to record per-generation convergence, so runs can
+	// be plotted afterwards
+	convergenceFile, err := os.Create("convergence.csv")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer convergenceFile.Close()
+	convergenceWriter := csv.NewWriter(convergenceFile)
+	defer convergenceWriter.Flush()
+	if err := convergenceWriter.Write([]string{"Problem Num", "Island", "Generation", "Best", "Mean", "Diversity"}); err != nil {
+		log.Fatal(err)
+	}
+
+	// Create a channel to hold the result of each problem
 	type result struct {
 		threadName     string
 		numGenerations int
@@ -261,76 +232,76 @@ func main() {
 		problemNum     int
 		distance       float64
 		elapsedTime    time.Duration
+		islands        []IslandResult
 	}
 	results := make(chan result, numProblems)
 
-	// Create and start threads
-	for i := 0; i < numThreads; i++ {
-		go func(threadName string) {
-			for problemNum, cities := range problems {
-				numGenerations := 100000
-				populationSize := 100
-				mutationRate := 0.05  // rand.Float64()
-				crossoverRate := 0.70 // rand.Float64()
-
-				start := time.Now()
-				population := make([]*Tour, populationSize)
-				for i := 0; i < populationSize; i++ {
-					population[i] = NewTour(cities)
-				}
+	// Solve every problem concurrently. Each problem is itself solved by
+	// numThreads islands evolving in parallel, periodically exchanging
+	// their best individuals, rather than numThreads goroutines each
+	// redundantly solving every problem on their own.
+	for problemNum, cities := range problems {
+		go func(problemNum int, cities []*City) {
+			threadName := fmt.Sprintf("Islands-%d", numThreads)
+
+			start := time.Now()
+			cfg := &GAConfig{
+				PopulationSize:  100,
+				MaxGenerations:  100000,
+				CrossoverRate:   0.70,
+				Crossover:       OrderCrossover,
+				Selection:       TournamentSelect(5),
+				MutationRate:    0.05,
+				MaxMutationRate: 0.30,
+				Patience:        2000,
+				Epsilon:         1e-6,
+				DiversityFloor:  1.0,
+				Migration: MigrationConfig{
+					Policy:   MigrationRing,
+					Size:     5,
+					Interval: 25,
+				},
+				HybridizeEvery:  50,
+				HybridizeTopK:   10,
+				TwoOptMaxPasses: 20,
+				DistanceMatrix:  NewDistanceMatrix(cities),
+			}
 
-				for i := range population {
-					population[i].CalculateFitness()
-				}
-				fitnessSum := 0.0
-				for i := range population {
-					fitnessSum += population[i].fitness
-				}
-				for i := range population {
-					population[i].fitness /= fitnessSum
-				}
+			bestTour, islands := RunIslandModel(cities, numThreads, cfg)
 
-				for i := 0; i < numGenerations; i++ {
-					for _, tour := range population {
-						tour.fitness = 1.0 / tour.distance
-					}
+			img := image.NewRGBA(image.Rect(0, 0, 256, 256))
+			overlay := fmt.Sprintf("Problem %d  Gen %d  Distance %.1f", problemNum, finalGeneration(bestTour, islands), bestTour.distance)
+			RenderTour(bestTour, img, RenderOptions{ShowLabels: true, Overlay: overlay})
+			f, _ := os.Create(fmt.Sprintf("./images/tour_%s_problem_%d.png", threadName, problemNum))
+			defer f.Close()
+			png.Encode(f, img)
 
-					population = Evolve(population, crossoverRate, mutationRate, cities)
-				}
+			svgPath := fmt.Sprintf("./images/tour_%s_problem_%d.svg", threadName, problemNum)
+			if err := SaveTourSVG(bestTour, svgPath, 256, 256, RenderOptions{ShowLabels: true, Overlay: overlay}); err != nil {
+				log.Println(err)
+			}
 
-				bestTour := population[0]
-				for _, tour := range population {
-					if tour.distance < bestTour.distance {
-						bestTour = tour
-					}
-				}
-				img := image.NewRGBA(image.Rect(0, 0, 256, 256))
-				DrawTour(bestTour, img)
-				f, _ := os.Create(fmt.Sprintf("./images/tour_thread_%s_problem_%d.png", threadName, problemNum))
-				defer f.Close()
-				png.Encode(f, img)
-
-				fmt.Println("Thread:", threadName, "Problem:", problemNum, "Distance:", bestTour.distance, "Time:", time.Since(start))
-				// Send result to channel
-				results <- result{
-					threadName:     threadName,
-					numGenerations: numGenerations,
-					populationSize: populationSize,
-					mutationRate:   mutationRate,
-					crossoverRate:  crossoverRate,
-					problemNum:     problemNum,
-					distance:       bestTour.distance,
-					elapsedTime:    time.Since(start),
-				}
+			fmt.Println("Problem:", problemNum, "Distance:", bestTour.distance, "Time:", time.Since(start))
+			// Send result to channel
+			results <- result{
+				threadName:     threadName,
+				numGenerations: cfg.MaxGenerations,
+				populationSize: cfg.PopulationSize,
+				mutationRate:   cfg.MutationRate,
+				crossoverRate:  cfg.CrossoverRate,
+				problemNum:     problemNum,
+				distance:       bestTour.distance,
+				elapsedTime:    time.Since(start),
+				islands:        islands,
 			}
-		}(fmt.Sprintf("Thread-%d", i+1))
+		}(problemNum, cities)
 	}
 
 	// Create a map to store the results
 	resultsMap := make(map[int][]result)
 
 	// Write the results to the map
-	for i := 0; i < numProblems*numThreads; i++ {
+	for i := 0; i < numProblems; i++ {
 		result := <-results
 		resultsMap[result.problemNum] = append(resultsMap[result.problemNum], result)
 	}
@@ -357,6 +328,22 @@ func main() {
 			if err := csvWriter.Write(record); err != nil {
 				log.Fatal(err)
 			}
+
+			for _, island := range result.islands {
+				for _, stat := range island.Stats {
+					row := []string{
+						strconv.Itoa(result.problemNum),
+						strconv.Itoa(island.Island),
+						strconv.Itoa(stat.Generation),
+						strconv.FormatFloat(stat.Best, 'f', 6, 64),
+						strconv.FormatFloat(stat.Mean, 'f', 6, 64),
+						strconv.FormatFloat(stat.Diversity, 'f', 6, 64),
+					}
+					if err := convergenceWriter.Write(row); err != nil {
+						log.Fatal(err)
+					}
+				}
+			}
 		}
 	}
 }
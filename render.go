@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"os"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// RenderOptions controls the extras the renderer can add on top of the
+// plain tour: city index labels and a text overlay (typically the
+// current generation and best distance, for progress screenshots taken
+// mid-run).
+type RenderOptions struct {
+	ShowLabels bool
+	Overlay    string
+}
+
+// DrawTour renders tour onto img using antialiased lines and filled city
+// circles. It keeps the signature the GA loop already calls; for labels
+// or an overlay, call RenderTour directly.
+func DrawTour(tour *Tour, img *image.RGBA) {
+	RenderTour(tour, img, RenderOptions{ShowLabels: true})
+}
+
+// DrawLine draws an antialiased line between city1 and city2 onto img.
+func DrawLine(city1, city2 *City, img *image.RGBA) {
+	rasterizeLine(img, city1.x, city1.y, city2.x, city2.y, 1.2, color.RGBA{0, 0, 0, 255})
+}
+
+// RenderTour draws tour's cities and path onto img, antialiased, with
+// optional city-index labels and a text overlay.
+func RenderTour(tour *Tour, img *image.RGBA, opts RenderOptions) {
+	for i, city := range tour.cities {
+		next := tour.cities[(i+1)%len(tour.cities)]
+		rasterizeLine(img, city.x, city.y, next.x, next.y, 1.2, color.RGBA{0, 0, 0, 255})
+	}
+
+	for i, city := range tour.cities {
+		rasterizeCircle(img, city.x, city.y, NodeSize/2, color.RGBA{30, 30, 30, 255})
+		if opts.ShowLabels {
+			drawLabel(img, city.x+NodeSize, city.y-NodeSize, fmt.Sprintf("%d", i), color.RGBA{0, 0, 0, 255})
+		}
+	}
+
+	if opts.Overlay != "" {
+		drawLabel(img, 4, 4, opts.Overlay, color.RGBA{200, 0, 0, 255})
+	}
+}
+
+// rasterizeLine draws an antialiased line of the given width using
+// golang.org/x/image/vector, which rasterizes the stroke as a filled
+// quad along the line's normal.
+func rasterizeLine(img *image.RGBA, x0, y0, x1, y1 int, width float32, col color.Color) {
+	bounds := img.Bounds()
+	r := vector.NewRasterizer(bounds.Dx(), bounds.Dy())
+
+	dx, dy := float32(x1-x0), float32(y1-y0)
+	length := float32(mathHypot(dx, dy))
+	if length == 0 {
+		length = 1
+	}
+	nx, ny := -dy/length*width/2, dx/length*width/2
+
+	fx0, fy0 := float32(x0), float32(y0)
+	fx1, fy1 := float32(x1), float32(y1)
+
+	r.MoveTo(fx0+nx, fy0+ny)
+	r.LineTo(fx1+nx, fy1+ny)
+	r.LineTo(fx1-nx, fy1-ny)
+	r.LineTo(fx0-nx, fy0-ny)
+	r.ClosePath()
+
+	mask := image.NewAlpha(bounds)
+	r.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+	draw.DrawMask(img, bounds, &image.Uniform{C: col}, image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// rasterizeCircle draws an antialiased filled circle centered at (cx,cy).
+func rasterizeCircle(img *image.RGBA, cx, cy, radius int, col color.Color) {
+	bounds := img.Bounds()
+	r := vector.NewRasterizer(bounds.Dx(), bounds.Dy())
+
+	const segments = 24
+	for i := 0; i <= segments; i++ {
+		angle := 2 * math.Pi * float64(i) / segments
+		x := float32(cx) + float32(radius)*float32(math.Cos(angle))
+		y := float32(cy) + float32(radius)*float32(math.Sin(angle))
+		if i == 0 {
+			r.MoveTo(x, y)
+		} else {
+			r.LineTo(x, y)
+		}
+	}
+	r.ClosePath()
+
+	mask := image.NewAlpha(bounds)
+	r.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+	draw.DrawMask(img, bounds, &image.Uniform{C: col}, image.Point{}, mask, image.Point{}, draw.Over)
+}
+
+// drawLabel draws text at (x,y) using the standard library's basic
+// bitmap font; good enough for city indices and a short overlay string.
+func drawLabel(img *image.RGBA, x, y int, text string, col color.Color) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{C: col},
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+// mathHypot avoids importing math solely for Hypot in a float32 context.
+func mathHypot(a, b float32) float64 {
+	return math.Hypot(float64(a), float64(b))
+}
+
+// SaveTourSVG writes tour as an SVG file to path, so tours can be embedded
+// in reports at any scale without the raster artifacts of a fixed-size
+// PNG. width and height set the SVG viewBox.
+func SaveTourSVG(tour *Tour, path string, width, height int, opts RenderOptions) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" viewBox=\"0 0 %d %d\">\n", width, height)
+	fmt.Fprintf(w, "  <rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+
+	fmt.Fprint(w, "  <polygon points=\"")
+	for i, city := range tour.cities {
+		if i > 0 {
+			fmt.Fprint(w, " ")
+		}
+		fmt.Fprintf(w, "%d,%d", city.x, city.y)
+	}
+	fmt.Fprint(w, "\" fill=\"none\" stroke=\"black\" stroke-width=\"1.2\"/>\n")
+
+	for i, city := range tour.cities {
+		fmt.Fprintf(w, "  <circle cx=\"%d\" cy=\"%d\" r=\"%d\" fill=\"#1e1e1e\"/>\n", city.x, city.y, NodeSize/2)
+		if opts.ShowLabels {
+			fmt.Fprintf(w, "  <text x=\"%d\" y=\"%d\" font-size=\"10\">%d</text>\n", city.x+NodeSize, city.y-NodeSize, i)
+		}
+	}
+
+	if opts.Overlay != "" {
+		fmt.Fprintf(w, "  <text x=\"4\" y=\"14\" font-size=\"12\" fill=\"red\">%s</text>\n", opts.Overlay)
+	}
+
+	fmt.Fprint(w, "</svg>\n")
+	return w.Flush()
+}
@@ -0,0 +1,63 @@
+package main
+
+import "math/rand"
+
+// SelectionFunc picks one parent from a population for breeding. It is
+// passed to Evolve so callers can choose and benchmark different
+// selection pressures.
+type SelectionFunc func(population []*Tour) *Tour
+
+// RouletteSelect selects a tour using fitness-proportional (roulette
+// wheel) selection. It sums raw fitness on every call, so unlike the
+// scheme this replaces, population fitness does not need to be
+// pre-normalized to sum to 1.
+func RouletteSelect(population []*Tour) *Tour {
+	fitnessSum := 0.0
+	for _, tour := range population {
+		fitnessSum += tour.fitness
+	}
+	randNum := rand.Float64() * fitnessSum
+	curSum := 0.0
+	for _, tour := range population {
+		curSum += tour.fitness
+		if curSum >= randNum {
+			return tour
+		}
+	}
+	return population[0]
+}
+
+// TournamentSelect returns a SelectionFunc that picks k random tours from
+// the population and returns the fittest of them. Unlike roulette
+// selection, its selection pressure doesn't collapse as the population
+// converges and fitness values become similar.
+func TournamentSelect(k int) SelectionFunc {
+	return func(population []*Tour) *Tour {
+		best := population[rand.Intn(len(population))]
+		for i := 1; i < k; i++ {
+			candidate := population[rand.Intn(len(population))]
+			if candidate.fitness > best.fitness {
+				best = candidate
+			}
+		}
+		return best
+	}
+}
+
+// RankSelect selects a tour with probability proportional to its rank
+// rather than its raw fitness. It assumes population is sorted ascending
+// by distance, as Evolve keeps it, so population[0] is the best tour and
+// receives the highest rank weight.
+func RankSelect(population []*Tour) *Tour {
+	n := len(population)
+	rankSum := n * (n + 1) / 2
+	randNum := rand.Intn(rankSum)
+	curSum := 0
+	for i, tour := range population {
+		curSum += n - i
+		if curSum > randNum {
+			return tour
+		}
+	}
+	return population[n-1]
+}
@@ -0,0 +1,63 @@
+package main
+
+import "testing"
+
+// newRankedTours returns n tours with distinct, ascending distances (and
+// matching fitness), as Evolve keeps population sorted, so tours[0] is
+// the best.
+func newRankedTours(n int) []*Tour {
+	tours := make([]*Tour, n)
+	for i := range tours {
+		d := float64(i + 1)
+		tours[i] = &Tour{distance: d, fitness: 1.0 / d}
+	}
+	return tours
+}
+
+// TestSelectionFuncsHandleSmallPopulations checks TournamentSelect and
+// RankSelect never return nil or panic on a single-tour population, or
+// when TournamentSelect's k exceeds the population size.
+func TestSelectionFuncsHandleSmallPopulations(t *testing.T) {
+	selections := map[string]SelectionFunc{
+		"TournamentSelect(k>len(population))": TournamentSelect(10),
+		"RankSelect":                          RankSelect,
+	}
+
+	for name, selection := range selections {
+		t.Run(name, func(t *testing.T) {
+			population := newRankedTours(1)
+			for i := 0; i < 50; i++ {
+				if got := selection(population); got == nil {
+					t.Fatalf("selection returned nil")
+				}
+			}
+		})
+	}
+}
+
+// TestRankSelectFavorsBetterTours checks that, over many trials,
+// RankSelect picks lower-rank (better, lower-distance) tours more often
+// than higher-rank ones.
+func TestRankSelectFavorsBetterTours(t *testing.T) {
+	population := newRankedTours(10)
+	picks := make([]int, len(population)) // picks[i] counts selections of population[i]
+	const trials = 20000
+	for i := 0; i < trials; i++ {
+		picked := RankSelect(population)
+		for idx, tour := range population {
+			if tour == picked {
+				picks[idx]++
+				break
+			}
+		}
+	}
+
+	if picks[0] <= picks[len(picks)-1] {
+		t.Fatalf("expected the best tour (rank 0) to be picked more often than the worst, got picks[0]=%d picks[last]=%d", picks[0], picks[len(picks)-1])
+	}
+	for i := 0; i < len(picks)-1; i++ {
+		if picks[i] < picks[i+1]-trials/100 {
+			t.Fatalf("expected picks to trend downward by rank, got picks[%d]=%d < picks[%d]=%d (beyond noise tolerance)", i, picks[i], i+1, picks[i+1])
+		}
+	}
+}
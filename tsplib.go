@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// edgeWeightType identifies how a TSPLIB instance's coordinates should be
+// interpreted when computing distances.
+type edgeWeightType string
+
+const (
+	edgeWeightEUC2D edgeWeightType = "EUC_2D"
+	edgeWeightGEO   edgeWeightType = "GEO"
+	edgeWeightATT   edgeWeightType = "ATT"
+)
+
+// geoToXY converts TSPLIB GEO coordinates (degrees.minutes, as found in the
+// NODE_COORD_SECTION of instances like ulysses16) into latitude/longitude
+// in radians, following the conversion described in the TSPLIB95 format doc.
+func geoToXY(coord float64) float64 {
+	deg := math.Trunc(coord)
+	min := coord - deg
+	return math.Pi * (deg + 5.0*min/3.0) / 180.0
+}
+
+// geoCoordScale multiplies geoToXY's radian output (typically in the
+// range [-pi, pi]) up into a fixed-point int with enough precision to
+// keep cities distinct and usable for rendering/rescaling; plain
+// truncation to int collapses every city onto (0,0).
+const geoCoordScale = 1e6
+
+// geoEarthRadiusKm is RRR in the TSPLIB95 GEO distance formula.
+const geoEarthRadiusKm = 6378.388
+
+// geoDistance computes the TSPLIB GEO great-circle distance between a and
+// b from their lat/lon (in radians), following the TSPLIB95 format doc
+// exactly, including its truncate-then-add-one rounding, so reported tour
+// lengths match published optimal distances for GEO instances.
+func geoDistance(a, b *City) float64 {
+	q1 := math.Cos(a.lon - b.lon)
+	q2 := math.Cos(a.lat - b.lat)
+	q3 := math.Cos(a.lat + b.lat)
+	return math.Trunc(geoEarthRadiusKm*math.Acos(0.5*((1+q1)*q2-(1-q1)*q3))) + 1.0
+}
+
+// attDistance computes the TSPLIB ATT pseudo-Euclidean distance between a
+// and b, following the TSPLIB95 format doc's nint-then-correct rounding
+// so reported tour lengths match published optimal distances for ATT
+// instances like att48.
+func attDistance(a, b *City) float64 {
+	dx := float64(a.x - b.x)
+	dy := float64(a.y - b.y)
+	rij := math.Sqrt((dx*dx + dy*dy) / 10.0)
+	tij := math.Round(rij)
+	if tij < rij {
+		return tij + 1
+	}
+	return tij
+}
+
+// ParseTSPFile reads a TSPLIB-format .tsp file and returns the cities listed
+// in its NODE_COORD_SECTION, tagged with the file's EDGE_WEIGHT_TYPE so
+// City.Distance computes the right formula. EUC_2D coordinates are used
+// as-is. GEO coordinates are converted from degrees.minutes into
+// latitude/longitude in radians: x/y store a scaled-up fixed-point
+// version so the cities stay distinct and can still be fed straight into
+// DrawTour/RescaleCities, while the original radians are kept in lat/lon
+// for the real GEO great-circle distance formula. ATT (pseudo-Euclidean,
+// used by att48 and similar) coordinates are used as-is; the pseudo-
+// Euclidean correction is applied in Distance, not to the coordinates.
+func ParseTSPFile(path string) ([]*City, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("tsplib: %w", err)
+	}
+	defer f.Close()
+
+	var weightType edgeWeightType = edgeWeightEUC2D
+	var cities []*City
+	inCoordSection := false
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line == "EOF" {
+			break
+		}
+
+		if !inCoordSection {
+			if strings.HasPrefix(line, "EDGE_WEIGHT_TYPE") {
+				parts := strings.SplitN(line, ":", 2)
+				if len(parts) == 2 {
+					weightType = edgeWeightType(strings.TrimSpace(parts[1]))
+				}
+				continue
+			}
+			if line == "NODE_COORD_SECTION" {
+				inCoordSection = true
+			}
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		x, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("tsplib: bad x coordinate %q: %w", fields[1], err)
+		}
+		y, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("tsplib: bad y coordinate %q: %w", fields[2], err)
+		}
+
+		if weightType == edgeWeightGEO {
+			lat, lon := geoToXY(x), geoToXY(y)
+			cities = append(cities, &City{
+				x:          int(lat * geoCoordScale),
+				y:          int(lon * geoCoordScale),
+				weightType: edgeWeightGEO,
+				lat:        lat,
+				lon:        lon,
+			})
+			continue
+		}
+
+		cities = append(cities, &City{x: int(x), y: int(y), weightType: weightType})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("tsplib: %w", err)
+	}
+	if len(cities) == 0 {
+		return nil, fmt.Errorf("tsplib: %s has no NODE_COORD_SECTION entries", path)
+	}
+
+	return cities, nil
+}
+
+// LoadTSPBenchmarks loads every *.tsp file in path (or just path itself, if
+// it names a single file) and returns the parsed city lists keyed by
+// instance name, e.g. "berlin52" for berlin52.tsp.
+func LoadTSPBenchmarks(path string) (map[string][]*City, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("tsplib: %w", err)
+	}
+
+	var files []string
+	if info.IsDir() {
+		matches, err := filepath.Glob(filepath.Join(path, "*.tsp"))
+		if err != nil {
+			return nil, fmt.Errorf("tsplib: %w", err)
+		}
+		files = matches
+	} else {
+		files = []string{path}
+	}
+	sort.Strings(files)
+
+	problems := make(map[string][]*City, len(files))
+	for _, file := range files {
+		cities, err := ParseTSPFile(file)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		problems[name] = cities
+	}
+	return problems, nil
+}
+
+// RescaleCities returns a copy of cities linearly rescaled, preserving
+// aspect ratio, to fit within a maxX x maxY canvas with a small margin so
+// that node circles drawn by DrawTour don't clip at the edges. weightType
+// and, for GEO instances, the original lat/lon are carried over unscaled
+// so Distance keeps computing the real formula after rescaling.
+func RescaleCities(cities []*City, maxX, maxY int) []*City {
+	if len(cities) == 0 {
+		return nil
+	}
+
+	minPx, maxPx := cities[0].x, cities[0].x
+	minPy, maxPy := cities[0].y, cities[0].y
+	for _, c := range cities {
+		if c.x < minPx {
+			minPx = c.x
+		}
+		if c.x > maxPx {
+			maxPx = c.x
+		}
+		if c.y < minPy {
+			minPy = c.y
+		}
+		if c.y > maxPy {
+			maxPy = c.y
+		}
+	}
+
+	spanX := float64(maxPx - minPx)
+	spanY := float64(maxPy - minPy)
+	if spanX == 0 {
+		spanX = 1
+	}
+	if spanY == 0 {
+		spanY = 1
+	}
+
+	margin := float64(NodeSize * 2)
+	scale := math.Min((float64(maxX)-2*margin)/spanX, (float64(maxY)-2*margin)/spanY)
+
+	rescaled := make([]*City, len(cities))
+	for i, c := range cities {
+		rescaled[i] = &City{
+			x:          int(margin + float64(c.x-minPx)*scale),
+			y:          int(margin + float64(c.y-minPy)*scale),
+			weightType: c.weightType,
+			lat:        c.lat,
+			lon:        c.lon,
+		}
+	}
+	return rescaled
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTSPFile(t *testing.T, contents string) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "instance.tsp")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestParseTSPFileGEODoesNotCollapseCities guards against the truncation
+// bug where GEO radians (typically 0.3-0.9) were cast straight to int,
+// landing every city on (0,0).
+func TestParseTSPFileGEODoesNotCollapseCities(t *testing.T) {
+	path := writeTSPFile(t, `NAME: test-geo
+TYPE: TSP
+EDGE_WEIGHT_TYPE: GEO
+NODE_COORD_SECTION
+1 38.24 20.42
+2 39.57 26.15
+3 40.56 25.32
+EOF
+`)
+	cities, err := ParseTSPFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[[2]int]bool)
+	for _, c := range cities {
+		if c.x == 0 && c.y == 0 {
+			t.Fatalf("city collapsed to (0,0): %+v", c)
+		}
+		key := [2]int{c.x, c.y}
+		if seen[key] {
+			t.Fatalf("two cities landed on the same point %v", key)
+		}
+		seen[key] = true
+	}
+}
+
+// TestGeoDistanceMatchesTSPLIBFormula checks geoDistance against a
+// hand-computed value for two ulysses16-style coordinates.
+func TestGeoDistanceMatchesTSPLIBFormula(t *testing.T) {
+	a := &City{weightType: edgeWeightGEO, lat: geoToXY(38.24), lon: geoToXY(20.42)}
+	b := &City{weightType: edgeWeightGEO, lat: geoToXY(39.57), lon: geoToXY(26.15)}
+
+	got := a.Distance(b)
+
+	q1 := math.Cos(a.lon - b.lon)
+	q2 := math.Cos(a.lat - b.lat)
+	q3 := math.Cos(a.lat + b.lat)
+	want := math.Trunc(6378.388*math.Acos(0.5*((1+q1)*q2-(1-q1)*q3))) + 1.0
+
+	if got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if got <= 0 {
+		t.Fatalf("expected a positive distance, got %v", got)
+	}
+}
+
+// TestATTDistanceMatchesTSPLIBFormula checks attDistance's rounding
+// against the TSPLIB95 nint-then-correct rule on a couple of fixed
+// coordinate pairs.
+func TestATTDistanceMatchesTSPLIBFormula(t *testing.T) {
+	cases := []struct {
+		ax, ay, bx, by int
+	}{
+		{0, 0, 100, 0},
+		{0, 0, 7, 3},
+		{50, 50, 0, 0},
+	}
+	for _, c := range cases {
+		a := &City{x: c.ax, y: c.ay, weightType: edgeWeightATT}
+		b := &City{x: c.bx, y: c.by, weightType: edgeWeightATT}
+
+		dx := float64(c.ax - c.bx)
+		dy := float64(c.ay - c.by)
+		rij := math.Sqrt((dx*dx + dy*dy) / 10.0)
+		tij := math.Round(rij)
+		want := tij
+		if tij < rij {
+			want = tij + 1
+		}
+
+		if got := a.Distance(b); got != want {
+			t.Fatalf("Distance(%v, %v) = %v, want %v", c, c, got, want)
+		}
+	}
+}
+
+// TestParseTSPFileTagsATTWeightType ensures ATT cities are parsed with
+// weightType set so Distance uses the real pseudo-Euclidean formula
+// instead of silently falling back to plain Euclidean.
+func TestParseTSPFileTagsATTWeightType(t *testing.T) {
+	path := writeTSPFile(t, `NAME: test-att
+TYPE: TSP
+EDGE_WEIGHT_TYPE: ATT
+NODE_COORD_SECTION
+1 0 0
+2 100 0
+EOF
+`)
+	cities, err := ParseTSPFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cities[0].weightType != edgeWeightATT {
+		t.Fatalf("got weightType %q, want %q", cities[0].weightType, edgeWeightATT)
+	}
+	euclidean := 100.0
+	if d := cities[0].Distance(cities[1]); d == euclidean {
+		t.Fatalf("Distance returned plain Euclidean (%v) instead of the ATT pseudo-Euclidean formula", d)
+	}
+}